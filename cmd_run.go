@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/clusterscan"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/incluster"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+// defaultJobImage is the image the bootstrapped scan Job/CronJob runs unless
+// --image overrides it.
+const defaultJobImage = "ghcr.io/alexandredevely/kubectl-detector-for-docker-socket:latest"
+
+// runJobCommand implements `kubectl detector-for-docker-socket run`, which
+// bootstraps a Job (or, with --schedule, a recurring CronJob) that scans the
+// whole cluster from inside it. --in-cluster is how that Job's container
+// re-invokes the binary to actually perform the scan; it's not meant to be
+// set by hand.
+func runJobCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+
+	inCluster := fs.Bool("in-cluster", false, "Perform the scan itself using in-cluster credentials (set by the Job container, not meant to be passed by hand)")
+	image := fs.String("image", defaultJobImage, "Container image the scan Job/CronJob runs")
+	namespace := fs.String("namespace", "default", "Namespace to create the Job/CronJob and its RBAC objects in")
+	serviceAccount := fs.String("service-account", "detector-for-docker-socket", "ServiceAccount to create and grant read-only cluster access to")
+	nodeSelector := fs.StringToString("node-selector", nil, "Node selector for the scan pod, e.g. kubernetes.io/os=linux")
+	tolerations := fs.StringArray("toleration", nil, "Toleration for the scan pod as key[=value][:effect], may be repeated")
+	outputConfigMap := fs.String("output-configmap", "", "Write the JSON report to this ConfigMap once the scan completes")
+	schedule := fs.String("schedule", "", "Cron schedule; when set, creates a recurring CronJob instead of a one-shot Job")
+	rulesFlag := fs.String("rules", rulesPath, "Path to a custom rules YAML file, passed through to the scan pod")
+	failOnFlag := fs.String("fail-on", failOn, "Minimum severity that causes the scan pod to exit non-zero")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *inCluster {
+		return runInClusterScan(*rulesFlag, *failOnFlag, *outputConfigMap)
+	}
+
+	opts := incluster.Options{
+		Image:           *image,
+		Namespace:       *namespace,
+		ServiceAccount:  *serviceAccount,
+		NodeSelector:    *nodeSelector,
+		Tolerations:     incluster.ParseTolerations(*tolerations),
+		OutputConfigMap: *outputConfigMap,
+		Schedule:        *schedule,
+		Rules:           *rulesFlag,
+		FailOn:          *failOnFlag,
+	}
+
+	configFlags := genericclioptions.NewConfigFlags(true)
+	kubeConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("error loading kubeconfig: %v", err)
+	}
+	clientset := kubernetes.NewForConfigOrDie(kubeConfig)
+
+	return incluster.Bootstrap(context.Background(), clientset, opts, os.Stdout)
+}
+
+// runInClusterScan is the entrypoint the bootstrapped Job container runs
+// (`run --in-cluster`): it scans the whole cluster using its own
+// ServiceAccount instead of a kubeconfig, prints the report as JSON, and
+// optionally persists it to a ConfigMap.
+func runInClusterScan(rulesFlag, failOnThreshold, outputConfigMap string) error {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return fmt.Errorf("not running in a cluster: %w", err)
+	}
+	clientset := kubernetes.NewForConfigOrDie(restConfig)
+
+	engine, err := loadEngine(rulesFlag)
+	if err != nil {
+		return err
+	}
+
+	rpt := &report.Report{Mode: report.ModeCluster}
+	scanErr := clusterscan.All(context.Background(), clientset, "ALL", engine, rpt)
+
+	outputFormat = "json"
+	if printErr := printReport(rpt); printErr != nil {
+		return printErr
+	}
+
+	if outputConfigMap != "" {
+		if cmErr := incluster.WriteReportConfigMap(context.Background(), clientset, outputConfigMap, rpt); cmErr != nil {
+			return cmErr
+		}
+	}
+
+	if scanErr != nil {
+		return scanErr
+	}
+	if shouldExitWithError(rpt, failOnThreshold) {
+		os.Exit(1)
+	}
+	return nil
+}
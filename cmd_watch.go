@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/clusterscan"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+// watchMode implements --watch: it keeps informers running and prints each
+// new finding as a JSON line to stdout until interrupted, then exits 0. It
+// never returns on a clean shutdown, only on a setup error.
+func watchMode(clientset kubernetes.Interface, namespaceName string, engine *rules.Engine) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	enc := json.NewEncoder(os.Stdout)
+	onFinding := func(f report.Finding) {
+		if err := enc.Encode(f); err != nil {
+			fmt.Fprintf(os.Stderr, "error: unable to encode finding: %v\n", err)
+		}
+	}
+
+	if err := clusterscan.Watch(ctx, clientset, namespaceName, engine, onFinding); err != nil {
+		return err
+	}
+
+	os.Exit(0)
+	return nil
+}
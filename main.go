@@ -1,50 +1,83 @@
 package main
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/tabwriter"
 
 	flag "github.com/spf13/pflag"
 
-	appsv1 "k8s.io/api/apps/v1"
-	batchv1 "k8s.io/api/batch/v1"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/actioners"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/clusterscan"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/manifest"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
 )
 
 var verbose bool
-var exitErr bool
-var sockFound bool
+var failOn string
+var rulesPath string
+var outputFormat string
 
 func main() {
-	if err := run(); err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		if err := runJobCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	rpt, err := run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v", err)
 		os.Exit(1)
 	}
-	if exitErr && sockFound {
+	if shouldExitWithError(rpt, failOn) {
 		os.Exit(1)
 	} else {
 		os.Exit(0)
 	}
 }
 
-func run() error {
+// shouldExitWithError reports whether rpt contains findings at or above the
+// --fail-on threshold.
+func shouldExitWithError(rpt *report.Report, threshold string) bool {
+	counts := rpt.CountBySeverity()
+	switch rules.Severity(threshold) {
+	case rules.SeverityCritical:
+		return counts[rules.SeverityCritical] > 0
+	case rules.SeverityWarning:
+		return counts[rules.SeverityCritical] > 0 || counts[rules.SeverityWarning] > 0
+	default:
+		return false
+	}
+}
+
+func run() (*report.Report, error) {
 	// flags
 	requestedNamespace := flag.StringP("namespace", "n", "ALL", "Namespace to search for pods")
-	requestedFile := flag.StringP("filename", "f", "", "File or directory to scan")
+	requestedFile := flag.StringP("filename", "f", "", "File or directory to scan, or - to read from stdin")
+	chartFlag := flag.String("chart", "", "Render a Helm chart (path[:values.yaml]) and scan its output")
+	kustomizeFlag := flag.String("kustomize", "", "Build a Kustomize overlay and scan its output")
 	help := flag.BoolP("help", "h", false, "Print usage")
-	flag.BoolVarP(&exitErr, "exit-with-error", "e", false, "Exit with error code if docker.sock found")
+	flag.StringVar(&rulesPath, "rules", "", "Path to a custom rules YAML file (defaults to the built-in ruleset)")
+	flag.StringVar(&failOn, "fail-on", "", "Minimum severity (warning|critical) that causes a non-zero exit code; empty (the default) never fails the exit code on findings")
+	flag.StringVarP(&outputFormat, "output", "o", "table", "Output format: table|json|yaml|sarif")
 	flag.BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	remediate := flag.String("remediate", "", "Comma-separated remediation actions to apply to findings (label,annotate,cordon-owner,evict,networkpolicy)")
+	yes := flag.Bool("yes", false, "Actually perform --remediate actions instead of only printing what they would do")
+	concurrency := flag.Int("concurrency", 0, "Max namespaces scanned concurrently (default: min(NumCPU, namespace count))")
+	watch := flag.Bool("watch", false, "Keep running and stream new findings as JSON lines when workloads change, instead of exiting after one scan")
 
 	flag.Parse()
 
@@ -53,300 +86,168 @@ func run() error {
 		os.Exit(0)
 	}
 
-	// initialize tabwriter
-	w := new(tabwriter.Writer)
-
-	// minwidth, tabwidth, padding, padchar, flags
-	w.Init(os.Stdout, 8, 8, 0, '\t', 0)
-
-	defer w.Flush()
-
-	// only scan local files if -f is provided
-	if len(*requestedFile) > 0 {
-		var files []string
-		fmt.Fprintf(w, "%s\t%s\t%s\t\n", "FILE", "LINE", "STATUS")
+	if failOn != "" && rules.Severity(failOn) != rules.SeverityWarning && rules.Severity(failOn) != rules.SeverityCritical {
+		return nil, fmt.Errorf("invalid --fail-on value %q: must be warning or critical", failOn)
+	}
 
-		fileInfo, err := os.Stat(*requestedFile)
-		if err != nil {
-			return fmt.Errorf("unable to open file: %v\n", *requestedFile)
-		}
+	engine, err := loadEngine(rulesPath)
+	if err != nil {
+		return nil, err
+	}
 
-		if fileInfo.IsDir() {
-			err = filepath.Walk(*requestedFile, func(path string, info os.FileInfo, err error) error {
-				pathInfo, err := os.Stat(path)
-				if !pathInfo.IsDir() {
-					files = append(files, path)
-				}
-				return nil
-			})
-			if err != nil {
-				fmt.Printf("something went wrong")
-				return err
+	// scan manifests offline (raw files/stdin, a Helm chart, or a Kustomize
+	// overlay) instead of a live cluster whenever any of those are requested
+	sources, err := collectManifestSources(*requestedFile, *chartFlag, *kustomizeFlag)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) > 0 {
+		rpt := &report.Report{Mode: report.ModeFile}
+		scanner := manifest.New(engine)
+		for _, src := range sources {
+			scanErr := scanner.ScanReader(src.reader, src.name, rpt)
+			src.reader.Close()
+			if scanErr != nil {
+				return rpt, scanErr
 			}
-
-		} else {
-			// filePath is a regular file
-			files = append(files, *requestedFile)
-		}
-
-		printFiles(w, files)
-
-		if exitErr && sockFound {
-			os.Exit(1)
-		} else {
-			os.Exit(0)
 		}
+		return rpt, printReport(rpt)
 	}
 
+	rpt := &report.Report{Mode: report.ModeCluster}
+
 	// setup kubeconfig client
 	configFlags := genericclioptions.NewConfigFlags(true).WithDiscoveryBurst(300).WithDiscoveryQPS(50.0)
 	kubeConfig, err := configFlags.ToRESTConfig()
 	if err != nil {
-		return fmt.Errorf("error loading kubeconfig: %v", err)
+		return nil, fmt.Errorf("error loading kubeconfig: %v", err)
 	}
 	clientset := kubernetes.NewForConfigOrDie(kubeConfig)
 
-	// Column headers for live cluster scan
-	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", "NAMESPACE", "TYPE", "NAME", "STATUS")
-
-	if *requestedNamespace != "ALL" {
-		if verbose {
-			fmt.Printf("user specified namespace: %s\n", *requestedNamespace)
-		}
-		namespace, err := clientset.CoreV1().Namespaces().Get(context.Background(), *requestedNamespace, metav1.GetOptions{})
-		if err != nil {
-			return fmt.Errorf("unable to fetch namespace %q: %v", *requestedNamespace, err)
-		}
-		return printResources(*namespace, clientset, w)
-	} else {
-		namespaceList, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return fmt.Errorf("unable to list namespaces: %v", err)
-		}
-
-		namespaceErrors := make([]error, 0)
-		// loop through each namespace
-		for _, namespace := range namespaceList.Items {
-			if err := printResources(namespace, clientset, w); err != nil {
-				namespaceErrors = append(namespaceErrors, err)
-			}
-		}
-		if len(namespaceErrors) > 0 {
-			return utilerrors.NewAggregate(namespaceErrors)
-		}
+	if verbose && *requestedNamespace != "ALL" {
+		fmt.Fprintf(os.Stderr, "user specified namespace: %s\n", *requestedNamespace)
 	}
-	return nil
-}
-
-func printResources(namespace corev1.Namespace, clientset *kubernetes.Clientset, w *tabwriter.Writer) error {
-	namespaceName := namespace.ObjectMeta.Name
 
-	nsDeployments := make(map[string]*appsv1.Deployment)
-	nsDaemonsets := make(map[string]*appsv1.DaemonSet)
-	nsStatefulsets := make(map[string]*appsv1.StatefulSet)
-	nsJobs := make(map[string]*batchv1.Job)
-	nsCronJobs := make(map[string]*batchv1.CronJob)
-
-	// Get a list of all pods in the namespace
-	podList, err := clientset.CoreV1().Pods(namespaceName).List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("unable to fetch pods: %v", err)
+	if *watch {
+		return nil, watchMode(clientset, *requestedNamespace, engine)
 	}
 
-	errorList := make([]error, 0)
-	// loop through each pod
-	for _, p := range podList.Items {
-		// print object
-		// fmt.Printf("%+v\n", p.ObjectMeta.OwnerReferences)
-
-		// only look at pods that have volumes
-		if len(p.Spec.Volumes) != 0 {
-			// fmt.Printf("%T\n", p.ObjectMeta.OwnerReferences)
-
-			// true if pod has an owner (eg deployment, daemonset, job)
-			if len(p.ObjectMeta.OwnerReferences) != 0 {
-				podOwner := p.OwnerReferences[0].Name
-
-				// Supported owner types are
-				// ReplicaSet (looks up deployment)
-				// DaemonSet
-				// StatefulSet
-				// ...
-				switch p.ObjectMeta.OwnerReferences[0].Kind {
-				case "ReplicaSet":
-					replica, rsErr := clientset.AppsV1().ReplicaSets(namespace.Name).Get(context.TODO(), podOwner, metav1.GetOptions{})
-					if rsErr != nil {
-						errorList = append(errorList, rsErr)
-						continue
-					}
-
-					deployment, deployErr := clientset.AppsV1().Deployments(namespace.Name).Get(context.TODO(), replica.OwnerReferences[0].Name, metav1.GetOptions{})
-					if deployErr != nil {
-						errorList = append(errorList, deployErr)
-						continue
-					}
-
-					// append the current deployment to look up later
-					// only append if it's not already in the list
-					if _, ok := nsDeployments[deployment.Name]; !ok {
-						nsDeployments[deployment.Name] = deployment
-					}
-				case "DaemonSet":
-					daemonset, dsErr := clientset.AppsV1().DaemonSets(namespace.Name).Get(context.TODO(), podOwner, metav1.GetOptions{})
-					if dsErr != nil {
-						errorList = append(errorList, dsErr)
-						continue
-					}
-
-					// append the current daemonset to look up later
-					if _, ok := nsDaemonsets[daemonset.Name]; !ok {
-						nsDaemonsets[daemonset.Name] = daemonset
-					}
-				case "StatefulSet":
-					statefulset, ssErr := clientset.AppsV1().StatefulSets(namespace.Name).Get(context.TODO(), podOwner, metav1.GetOptions{})
-					if ssErr != nil {
-						errorList = append(errorList, ssErr)
-						continue
-					}
-
-					// append the current StatefulSet to look up later
-					if _, ok := nsStatefulsets[statefulset.Name]; !ok {
-						nsStatefulsets[statefulset.Name] = statefulset
-					}
-				case "Node":
-					// skip pods with owner type node because they're static pods
-					continue
-				case "Job":
-					job, jobErr := clientset.BatchV1().Jobs(namespace.Name).Get(context.TODO(), podOwner, metav1.GetOptions{})
-					if jobErr != nil {
-						errorList = append(errorList, jobErr)
-						continue
-					}
-
-					// check if the job has an owner
-					// If it does then it's part of a CronJob
-					if len(job.ObjectMeta.OwnerReferences) == 0 {
-						if _, ok := nsJobs[job.Name]; !ok {
-							nsJobs[job.Name] = job
-						}
-					} else {
-						// append to cronjob
-						cron, cronErr := clientset.BatchV1().CronJobs(namespace.Name).Get(context.TODO(), job.OwnerReferences[0].Name, metav1.GetOptions{})
-						if cronErr != nil {
-							errorList = append(errorList, cronErr)
-							continue
-						}
-
-						if _, ok := nsCronJobs[cron.Name]; !ok {
-							nsCronJobs[cron.Name] = cron
-						}
-					}
-
-				default:
-					// this prints for pods that say they have an owner but the owner doesn't exist
-					// happens with vcluster clusters and maybe other situations.
-					fmt.Printf("could not find resource manager for type %s for pod %s\n", p.OwnerReferences[0].Kind, p.Name)
-					continue
-				}
-			} else {
-				// Look up raw pods for volumes here
-				printVolumes(w, p.Spec.Volumes, namespaceName, "pod", p.Name)
-			}
-		}
-	}
-	// loop through all the unique deployments we found for volumes
-	for _, deploy := range nsDeployments {
-		printVolumes(w, deploy.Spec.Template.Spec.Volumes, namespaceName, "deployment", deploy.Name)
+	if err := clusterscan.AllConcurrent(context.Background(), clientset, *requestedNamespace, engine, rpt, *concurrency); err != nil {
+		return rpt, err
 	}
 
-	// loop through all the unique DaemonSets in the namespace
-	for _, daemonset := range nsDaemonsets {
-		volumeCounter := 0
-		for _, v := range daemonset.Spec.Template.Spec.Volumes {
-			if v.VolumeSource.HostPath != nil {
-				// fmt.Printf("testing %s\n", v.VolumeSource.HostPath.Path)
-				if strings.Contains(v.VolumeSource.HostPath.Path, "docker.sock") {
-					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", namespaceName, "daemonset", daemonset.Name, "mounted")
-					break
-				}
-			}
-			volumeCounter++
-			if volumeCounter == len(daemonset.Spec.Template.Spec.Volumes) && verbose {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", namespaceName, "daemonset", daemonset.Name, "not-mounted")
-			}
-		}
+	if err := printReport(rpt); err != nil {
+		return rpt, err
 	}
 
-	// loop through all the unique StatefulSets in the namespace
-	for _, statefulset := range nsStatefulsets {
-		printVolumes(w, statefulset.Spec.Template.Spec.Volumes, namespaceName, "statefulset", statefulset.Name)
+	if *remediate != "" {
+		if err := actioners.Run(context.Background(), clientset, rpt, strings.Split(*remediate, ","), !*yes, os.Stderr); err != nil {
+			return rpt, err
+		}
 	}
 
-	// loop through all the unique Jobs in the namespace
-	for _, job := range nsJobs {
-		printVolumes(w, job.Spec.Template.Spec.Volumes, namespaceName, "job", job.Name)
-	}
+	return rpt, nil
+}
 
-	// loop through all the unique CronJobs in the namespace
-	for _, cron := range nsCronJobs {
-		printVolumes(w, cron.Spec.JobTemplate.Spec.Template.Spec.Volumes, namespaceName, "cron", cron.Name)
+// loadEngine returns the custom rule engine at path, or the built-in
+// default ruleset when path is empty.
+func loadEngine(path string) (*rules.Engine, error) {
+	if path == "" {
+		return rules.Default()
 	}
+	return rules.Load(path)
+}
 
-	if len(errorList) > 0 {
-		return utilerrors.NewAggregate(errorList)
+// printReport renders rpt to stdout in the format requested via --output.
+func printReport(rpt *report.Report) error {
+	printFlags := genericclioptions.NewPrintFlags("")
+	printer, err := report.NewPrinter(outputFormat, printFlags, verbose)
+	if err != nil {
+		return err
 	}
-	return nil
+	return printer.Print(os.Stdout, rpt)
 }
 
-func printVolumes(w *tabwriter.Writer, volumes []corev1.Volume, namespace, resType, resName string) {
-	for _, v := range volumes {
-		if v.VolumeSource.HostPath != nil {
-			mounted := "not-mounted"
-			if strings.Contains(v.VolumeSource.HostPath.Path, "docker.sock") {
-				mounted = "mounted"
-				sockFound = true
-			}
-			if mounted == "mounted" || verbose {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", namespace, resType, resName, mounted)
-			}
-		}
-	}
+// manifestSource is one YAML stream to feed through the manifest scanner,
+// labeled with the name that should appear on any findings it produces.
+type manifestSource struct {
+	name   string
+	reader io.ReadCloser
 }
 
-func printFiles(w *tabwriter.Writer, filePaths []string) {
-	for _, file := range filePaths {
-		mounted := "not-mounted"
-		line, err := searchFile(file)
+// collectManifestSources resolves --filename/--chart/--kustomize into the
+// set of manifest streams to scan. Exactly one of the three is expected to
+// be set; chart and kustomize take priority over filename if more than one
+// is somehow provided. Returns no sources when none of the flags are set,
+// which tells the caller to fall back to a live cluster scan.
+func collectManifestSources(requestedFile, chart, kustomizeOverlay string) ([]manifestSource, error) {
+	switch {
+	case chart != "":
+		chartPath, valuesPath := splitChartFlag(chart)
+		data, err := manifest.RenderChart(chartPath, valuesPath)
 		if err != nil {
-			return
-		}
-		if line > 0 {
-			mounted = "mounted"
-			sockFound = true
+			return nil, err
 		}
-		if mounted == "mounted" || verbose {
-			fmt.Fprintf(w, "%s\t%v\t%s\t\n", file, line, mounted)
+		return []manifestSource{{name: chartPath, reader: io.NopCloser(bytes.NewReader(data))}}, nil
+
+	case kustomizeOverlay != "":
+		data, err := manifest.RenderKustomize(kustomizeOverlay)
+		if err != nil {
+			return nil, err
 		}
+		return []manifestSource{{name: kustomizeOverlay, reader: io.NopCloser(bytes.NewReader(data))}}, nil
+
+	case requestedFile == "-":
+		return []manifestSource{{name: "stdin", reader: io.NopCloser(os.Stdin)}}, nil
+
+	case requestedFile != "":
+		return collectFileSources(requestedFile)
 	}
+
+	return nil, nil
 }
 
-func searchFile(path string) (int, error) {
-	f, err := os.Open(path)
+// collectFileSources opens requestedFile, or every regular file beneath it
+// if it's a directory, as manifest sources.
+func collectFileSources(requestedFile string) ([]manifestSource, error) {
+	fileInfo, err := os.Stat(requestedFile)
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("unable to open file: %v", requestedFile)
 	}
-	defer f.Close()
 
-	// Splits on newlines by default.
-	scanner := bufio.NewScanner(f)
+	var paths []string
+	if fileInfo.IsDir() {
+		err = filepath.Walk(requestedFile, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		paths = append(paths, requestedFile)
+	}
 
-	line := 1
-	for scanner.Scan() {
-		if strings.Contains(scanner.Text(), "docker.sock") {
-			return line, nil
+	sources := make([]manifestSource, 0, len(paths))
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open file: %v", p)
 		}
+		sources = append(sources, manifestSource{name: p, reader: f})
+	}
+	return sources, nil
+}
 
-		line++
+// splitChartFlag splits a --chart value of the form "path[:values.yaml]".
+func splitChartFlag(chart string) (chartPath, valuesPath string) {
+	if idx := strings.LastIndex(chart, ":"); idx != -1 {
+		return chart[:idx], chart[idx+1:]
 	}
-	return 0, nil
+	return chart, ""
 }
@@ -0,0 +1,61 @@
+// Package actioners implements remediation actions that can be taken against
+// a live cluster workload flagged by a Finding: labelling or annotating it,
+// cordoning its owner, evicting the offending pod, or quarantining it with a
+// deny-all NetworkPolicy. Actioners never mutate the cluster unless dryRun is
+// false, and the CLI only ever passes dryRun=false when the user passed
+// --yes.
+package actioners
+
+import (
+	"context"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+// labelKey is the label the label actioner applies to a flagged workload, so
+// it shows up in `kubectl get -l`.
+const labelKey = "docker-socket-detector/violation"
+
+// Actioner is a single remediation action, selectable by name via
+// --remediate.
+type Actioner interface {
+	// Name is the value that selects this actioner in --remediate.
+	Name() string
+
+	// Act performs (or, when dryRun is true, describes) the remediation for
+	// f. The returned string is a human-readable summary of what was done
+	// or would be done.
+	Act(ctx context.Context, clientset kubernetes.Interface, f report.Finding, dryRun bool) (string, error)
+}
+
+var registry = map[string]Actioner{}
+
+func register(a Actioner) {
+	registry[a.Name()] = a
+}
+
+func init() {
+	register(labelActioner{})
+	register(annotateActioner{})
+	register(cordonOwnerActioner{})
+	register(evictActioner{})
+	register(networkPolicyActioner{})
+}
+
+// Get returns the registered actioner named name, if any.
+func Get(name string) (Actioner, bool) {
+	a, ok := registry[name]
+	return a, ok
+}
+
+// Names returns every registered actioner name, for validation error
+// messages.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,157 @@
+package actioners
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+func TestLabelActioner(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: "default"},
+	})
+
+	f := report.Finding{Kind: "pod", Namespace: "default", Name: "app-1", RuleID: "docker-sock"}
+
+	a := labelActioner{}
+	if _, err := a.Act(context.Background(), clientset, f, false); err != nil {
+		t.Fatalf("Act: %v", err)
+	}
+
+	pod, err := clientset.CoreV1().Pods("default").Get(context.Background(), "app-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if pod.Labels[labelKey] != "docker-sock" {
+		t.Errorf("labels = %v, want %s=docker-sock", pod.Labels, labelKey)
+	}
+}
+
+func TestLabelActionerDryRun(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: "default"},
+	})
+
+	f := report.Finding{Kind: "pod", Namespace: "default", Name: "app-1", RuleID: "docker-sock"}
+
+	a := labelActioner{}
+	if _, err := a.Act(context.Background(), clientset, f, true); err != nil {
+		t.Fatalf("Act: %v", err)
+	}
+
+	pod, err := clientset.CoreV1().Pods("default").Get(context.Background(), "app-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(pod.Labels) != 0 {
+		t.Errorf("dry-run should not mutate the pod, got labels %v", pod.Labels)
+	}
+}
+
+func TestEvictActionerDeletesPod(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: "default"},
+	})
+
+	f := report.Finding{Kind: "pod", Namespace: "default", Name: "app-1"}
+
+	a := evictActioner{}
+	if _, err := a.Act(context.Background(), clientset, f, false); err != nil {
+		t.Fatalf("Act: %v", err)
+	}
+
+	if _, err := clientset.CoreV1().Pods("default").Get(context.Background(), "app-1", metav1.GetOptions{}); err == nil {
+		t.Error("expected pod to have been deleted")
+	}
+}
+
+func TestEvictActionerNoPodsFound(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	f := report.Finding{Kind: "pod", Namespace: "default", Name: "missing"}
+
+	a := evictActioner{}
+	summary, err := a.Act(context.Background(), clientset, f, false)
+	if err != nil {
+		t.Fatalf("Act: %v", err)
+	}
+	if summary == "" {
+		t.Error("expected a summary describing no pods were found")
+	}
+}
+
+func TestPodsForFindingDeployment(t *testing.T) {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-abc123",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web"}},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-abc123-xyz",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc123"}},
+		},
+	}
+	otherPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unrelated",
+			Namespace: "default",
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(deployment, replicaSet, pod, otherPod)
+
+	pods, err := podsForFinding(context.Background(), clientset, report.Finding{Kind: "deployment", Namespace: "default", Name: "web"})
+	if err != nil {
+		t.Fatalf("podsForFinding: %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "web-abc123-xyz" {
+		t.Errorf("expected only the deployment's pod, got %v", pods)
+	}
+}
+
+func TestPodsForFindingCronJob(t *testing.T) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "nightly-28",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "nightly"}},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "nightly-28-xyz",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "nightly-28"}},
+		},
+	}
+
+	clientset := fake.NewSimpleClientset(job, pod)
+
+	pods, err := podsForFinding(context.Background(), clientset, report.Finding{Kind: "cronjob", Namespace: "default", Name: "nightly"})
+	if err != nil {
+		t.Fatalf("podsForFinding: %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "nightly-28-xyz" {
+		t.Errorf("expected only the cronjob's pod, got %v", pods)
+	}
+}
+
+func TestMergePatchUnsupportedKind(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	err := mergePatch(context.Background(), clientset, report.Finding{Kind: "unknown", Name: "x"}, []byte("{}"))
+	if err == nil {
+		t.Fatal("expected an error for an unsupported Finding.Kind")
+	}
+}
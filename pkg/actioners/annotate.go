@@ -0,0 +1,46 @@
+package actioners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+const (
+	scannedAtAnnotation = "docker-socket-detector/scanned-at"
+	findingAnnotation   = "docker-socket-detector/finding"
+)
+
+// annotateActioner records the scan time and a human-readable summary of the
+// finding as annotations on the flagged workload.
+type annotateActioner struct{}
+
+func (annotateActioner) Name() string { return "annotate" }
+
+func (a annotateActioner) Act(ctx context.Context, clientset kubernetes.Interface, f report.Finding, dryRun bool) (string, error) {
+	summary := fmt.Sprintf("annotate %s/%s with finding %s", f.Kind, f.Name, f.RuleID)
+	if dryRun {
+		return "[dry-run] " + summary, nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				scannedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+				findingAnnotation:   fmt.Sprintf("%s: %s", f.RuleID, f.Detail),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := mergePatch(ctx, clientset, f, patch); err != nil {
+		return "", fmt.Errorf("unable to annotate %s/%s: %w", f.Kind, f.Name, err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,111 @@
+package actioners
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+// mergePatch applies patch to the workload f refers to, dispatching to the
+// right typed client by f.Kind. Kind values are the ones clusterscan and
+// manifest produce: pod, deployment, daemonset, statefulset, replicaset,
+// job, cronjob.
+func mergePatch(ctx context.Context, clientset kubernetes.Interface, f report.Finding, patch []byte) error {
+	var err error
+	switch f.Kind {
+	case "pod":
+		_, err = clientset.CoreV1().Pods(f.Namespace).Patch(ctx, f.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "deployment":
+		_, err = clientset.AppsV1().Deployments(f.Namespace).Patch(ctx, f.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "daemonset":
+		_, err = clientset.AppsV1().DaemonSets(f.Namespace).Patch(ctx, f.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "statefulset":
+		_, err = clientset.AppsV1().StatefulSets(f.Namespace).Patch(ctx, f.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "replicaset":
+		_, err = clientset.AppsV1().ReplicaSets(f.Namespace).Patch(ctx, f.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "job":
+		_, err = clientset.BatchV1().Jobs(f.Namespace).Patch(ctx, f.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "cronjob":
+		_, err = clientset.BatchV1().CronJobs(f.Namespace).Patch(ctx, f.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("unsupported workload kind %q", f.Kind)
+	}
+	return err
+}
+
+// podsForFinding resolves the actual pod(s) behind f. For a bare pod finding
+// that's just the pod itself (or no pods at all, if it's since been
+// deleted); for a Deployment it's the pods owned by its current ReplicaSets,
+// and for a CronJob the pods owned by its Jobs. Other kinds (DaemonSet,
+// StatefulSet, Job) own pods directly.
+func podsForFinding(ctx context.Context, clientset kubernetes.Interface, f report.Finding) ([]corev1.Pod, error) {
+	if f.Kind == "pod" {
+		pod, err := clientset.CoreV1().Pods(f.Namespace).Get(ctx, f.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []corev1.Pod{*pod}, nil
+	}
+
+	ownerNames := map[string]bool{f.Name: true}
+
+	switch f.Kind {
+	case "deployment":
+		replicaSets, err := clientset.AppsV1().ReplicaSets(f.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		ownerNames = map[string]bool{}
+		for _, rs := range replicaSets.Items {
+			if hasOwner(rs.OwnerReferences, "Deployment", f.Name) {
+				ownerNames[rs.Name] = true
+			}
+		}
+	case "cronjob":
+		jobs, err := clientset.BatchV1().Jobs(f.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		ownerNames = map[string]bool{}
+		for _, j := range jobs.Items {
+			if hasOwner(j.OwnerReferences, "CronJob", f.Name) {
+				ownerNames[j.Name] = true
+			}
+		}
+	}
+
+	podList, err := clientset.CoreV1().Pods(f.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	for _, p := range podList.Items {
+		for _, owner := range p.OwnerReferences {
+			if ownerNames[owner.Name] {
+				pods = append(pods, p)
+				break
+			}
+		}
+	}
+	return pods, nil
+}
+
+func hasOwner(refs []metav1.OwnerReference, kind, name string) bool {
+	for _, r := range refs {
+		if r.Kind == kind && r.Name == name {
+			return true
+		}
+	}
+	return false
+}
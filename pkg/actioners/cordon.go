@@ -0,0 +1,74 @@
+package actioners
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+// cordonOwnerActioner stops the flagged workload from running new pods:
+// Deployments and StatefulSets are scaled to zero replicas, CronJobs are
+// suspended. Other kinds (bare Pods, DaemonSets, Jobs) have no equivalent
+// "stop scheduling" operation, so cordon-owner is a no-op for them.
+type cordonOwnerActioner struct{}
+
+func (cordonOwnerActioner) Name() string { return "cordon-owner" }
+
+func (a cordonOwnerActioner) Act(ctx context.Context, clientset kubernetes.Interface, f report.Finding, dryRun bool) (string, error) {
+	switch f.Kind {
+	case "deployment":
+		return a.scaleToZero(f, dryRun, func() error {
+			dep, err := clientset.AppsV1().Deployments(f.Namespace).Get(ctx, f.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			replicas := int32(0)
+			dep.Spec.Replicas = &replicas
+			_, err = clientset.AppsV1().Deployments(f.Namespace).Update(ctx, dep, metav1.UpdateOptions{})
+			return err
+		})
+	case "statefulset":
+		return a.scaleToZero(f, dryRun, func() error {
+			sts, err := clientset.AppsV1().StatefulSets(f.Namespace).Get(ctx, f.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			replicas := int32(0)
+			sts.Spec.Replicas = &replicas
+			_, err = clientset.AppsV1().StatefulSets(f.Namespace).Update(ctx, sts, metav1.UpdateOptions{})
+			return err
+		})
+	case "cronjob":
+		summary := fmt.Sprintf("suspend cronjob/%s", f.Name)
+		if dryRun {
+			return "[dry-run] " + summary, nil
+		}
+		cron, err := clientset.BatchV1().CronJobs(f.Namespace).Get(ctx, f.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		suspend := true
+		cron.Spec.Suspend = &suspend
+		if _, err := clientset.BatchV1().CronJobs(f.Namespace).Update(ctx, cron, metav1.UpdateOptions{}); err != nil {
+			return "", fmt.Errorf("unable to suspend cronjob/%s: %w", f.Name, err)
+		}
+		return summary, nil
+	default:
+		return fmt.Sprintf("cordon-owner: no scale-down operation for kind %q, skipped", f.Kind), nil
+	}
+}
+
+func (cordonOwnerActioner) scaleToZero(f report.Finding, dryRun bool, do func() error) (string, error) {
+	summary := fmt.Sprintf("scale %s/%s to 0 replicas", f.Kind, f.Name)
+	if dryRun {
+		return "[dry-run] " + summary, nil
+	}
+	if err := do(); err != nil {
+		return "", fmt.Errorf("unable to scale %s/%s to 0: %w", f.Kind, f.Name, err)
+	}
+	return summary, nil
+}
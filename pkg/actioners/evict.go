@@ -0,0 +1,45 @@
+package actioners
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+// evictActioner deletes the pod(s) behind the finding so their controller
+// reschedules them. Deleting a pod whose spec still mounts the sensitive
+// hostPath just reproduces the same violation, so the summary calls that out
+// as a warning rather than pretending eviction alone fixes anything.
+type evictActioner struct{}
+
+func (evictActioner) Name() string { return "evict" }
+
+func (a evictActioner) Act(ctx context.Context, clientset kubernetes.Interface, f report.Finding, dryRun bool) (string, error) {
+	pods, err := podsForFinding(ctx, clientset, f)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve pods for %s/%s: %w", f.Kind, f.Name, err)
+	}
+	if len(pods) == 0 {
+		return fmt.Sprintf("evict: no pods found for %s/%s", f.Kind, f.Name), nil
+	}
+
+	summary := fmt.Sprintf("evict %d pod(s) for %s/%s (warning: pods will be rescheduled with the same spec unless it's changed first)", len(pods), f.Kind, f.Name)
+	if dryRun {
+		return "[dry-run] " + summary, nil
+	}
+
+	var deleteErrs []error
+	for _, pod := range pods {
+		if err := clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+			deleteErrs = append(deleteErrs, err)
+		}
+	}
+	if len(deleteErrs) > 0 {
+		return "", fmt.Errorf("unable to evict one or more pods for %s/%s: %v", f.Kind, f.Name, deleteErrs)
+	}
+	return summary, nil
+}
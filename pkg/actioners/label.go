@@ -0,0 +1,37 @@
+package actioners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+// labelActioner applies a docker-socket-detector/violation=<ruleID> label to
+// the flagged workload, so it shows up in `kubectl get -l`.
+type labelActioner struct{}
+
+func (labelActioner) Name() string { return "label" }
+
+func (a labelActioner) Act(ctx context.Context, clientset kubernetes.Interface, f report.Finding, dryRun bool) (string, error) {
+	summary := fmt.Sprintf("label %s/%s %s=%s", f.Kind, f.Name, labelKey, f.RuleID)
+	if dryRun {
+		return "[dry-run] " + summary, nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{labelKey: f.RuleID},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := mergePatch(ctx, clientset, f, patch); err != nil {
+		return "", fmt.Errorf("unable to label %s/%s: %w", f.Kind, f.Name, err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,41 @@
+package actioners
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+// networkPolicyActioner quarantines the flagged workload by creating a
+// deny-all NetworkPolicy selecting it. Finding doesn't carry the workload's
+// actual pod labels, so the generated policy assumes the common "app: <name>"
+// label convention; clusters that label workloads differently will need to
+// adjust the generated selector by hand.
+type networkPolicyActioner struct{}
+
+func (networkPolicyActioner) Name() string { return "networkpolicy" }
+
+func (a networkPolicyActioner) Act(ctx context.Context, clientset kubernetes.Interface, f report.Finding, dryRun bool) (string, error) {
+	name := fmt.Sprintf("quarantine-%s", f.Name)
+	summary := fmt.Sprintf("create deny-all networkpolicy/%s selecting app=%s in namespace %s", name, f.Name, f.Namespace)
+	if dryRun {
+		return "[dry-run] " + summary, nil
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: f.Namespace},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": f.Name}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+	if _, err := clientset.NetworkingV1().NetworkPolicies(f.Namespace).Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+		return "", fmt.Errorf("unable to create networkpolicy %q: %w", name, err)
+	}
+	return summary, nil
+}
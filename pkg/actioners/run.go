@@ -0,0 +1,38 @@
+package actioners
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+// Run applies each actioner named in names to every finding in rpt, writing
+// a one-line summary of each action to out. dryRun true means no actioner
+// mutates anything - it only reports what it would do.
+func Run(ctx context.Context, clientset kubernetes.Interface, rpt *report.Report, names []string, dryRun bool, out io.Writer) error {
+	selected := make([]Actioner, 0, len(names))
+	for _, name := range names {
+		a, ok := Get(name)
+		if !ok {
+			return fmt.Errorf("unknown remediation action %q (available: %s)", name, strings.Join(Names(), ", "))
+		}
+		selected = append(selected, a)
+	}
+
+	for _, f := range rpt.Findings {
+		for _, a := range selected {
+			summary, err := a.Act(ctx, clientset, f, dryRun)
+			if err != nil {
+				fmt.Fprintf(out, "%s: error: %v\n", a.Name(), err)
+				continue
+			}
+			fmt.Fprintf(out, "%s: %s\n", a.Name(), summary)
+		}
+	}
+	return nil
+}
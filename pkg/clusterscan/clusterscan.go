@@ -0,0 +1,47 @@
+// Package clusterscan evaluates live cluster workloads against a rule
+// engine. It's shared by the default live-cluster scan and the in-cluster
+// Job entrypoint (`run --in-cluster`), so both produce identical findings.
+//
+// Owner lookups (ReplicaSet -> Deployment, Job -> CronJob, ...) are served
+// from informer caches via Scanner rather than one API call per pod, so a
+// scan scales to clusters with thousands of pods. All/AllConcurrent are
+// convenience wrappers for callers that just want a one-shot scan; Watch
+// keeps informers running and streams findings as workloads change.
+package clusterscan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+// defaultResync is how often informer caches do a full relist independent of
+// the watch stream, to paper over any missed events.
+const defaultResync = 30 * time.Minute
+
+// All scans namespaceName, or every namespace in the cluster when
+// namespaceName is "ALL", appending findings to rpt. It's a convenience
+// wrapper around Scanner for callers that don't need to keep informers
+// running across multiple scans.
+func All(ctx context.Context, clientset kubernetes.Interface, namespaceName string, engine *rules.Engine, rpt *report.Report) error {
+	return AllConcurrent(ctx, clientset, namespaceName, engine, rpt, 0)
+}
+
+// AllConcurrent is All with an explicit cap on how many namespaces are
+// scanned at once. A concurrency of 0 selects DefaultConcurrency.
+func AllConcurrent(ctx context.Context, clientset kubernetes.Interface, namespaceName string, engine *rules.Engine, rpt *report.Report, concurrency int) error {
+	scanner := NewScanner(clientset, defaultResync)
+
+	syncCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := scanner.Start(syncCtx); err != nil {
+		return fmt.Errorf("unable to start informers: %w", err)
+	}
+
+	return scanner.ScanAll(ctx, namespaceName, engine, rpt, concurrency)
+}
@@ -0,0 +1,92 @@
+package clusterscan
+
+import (
+	"fmt"
+	"os"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// resolveOwner returns the workload that should be scanned on behalf of
+// pod: the pod itself when it has no owner, its Deployment/DaemonSet/
+// StatefulSet/Job/CronJob otherwise. ok is false with a nil err for a pod
+// owned by a Node (a static pod) or by a kind we don't recognize (seen with
+// vcluster and similar setups) - both are skipped rather than failing the
+// whole scan, matching the old pre-informer behavior.
+func (s *Scanner) resolveOwner(pod *corev1.Pod) (kind, name string, spec corev1.PodSpec, ok bool, err error) {
+	if len(pod.OwnerReferences) == 0 {
+		return "pod", pod.Name, pod.Spec, true, nil
+	}
+
+	owner := pod.OwnerReferences[0]
+	switch owner.Kind {
+	case "ReplicaSet":
+		deployment, err := s.deploymentForReplicaSet(pod.Namespace, owner.Name)
+		if err != nil {
+			return "", "", corev1.PodSpec{}, false, err
+		}
+		return "deployment", deployment.Name, deployment.Spec.Template.Spec, true, nil
+
+	case "DaemonSet":
+		daemonset, err := s.daemonSetLister.DaemonSets(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			return "", "", corev1.PodSpec{}, false, err
+		}
+		return "daemonset", daemonset.Name, daemonset.Spec.Template.Spec, true, nil
+
+	case "StatefulSet":
+		statefulset, err := s.statefulSetLister.StatefulSets(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			return "", "", corev1.PodSpec{}, false, err
+		}
+		return "statefulset", statefulset.Name, statefulset.Spec.Template.Spec, true, nil
+
+	case "Job":
+		job, err := s.jobLister.Jobs(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			return "", "", corev1.PodSpec{}, false, err
+		}
+		if cron, hasCron, err := s.cronJobForJob(job); err != nil {
+			return "", "", corev1.PodSpec{}, false, err
+		} else if hasCron {
+			return "cronjob", cron.Name, cron.Spec.JobTemplate.Spec.Template.Spec, true, nil
+		}
+		return "job", job.Name, job.Spec.Template.Spec, true, nil
+
+	case "Node":
+		// static pod, not owned by a workload we can evaluate
+		return "", "", corev1.PodSpec{}, false, nil
+
+	default:
+		fmt.Fprintf(os.Stderr, "could not find resource manager for type %s for pod %s\n", owner.Kind, pod.Name)
+		return "", "", corev1.PodSpec{}, false, nil
+	}
+}
+
+// deploymentForReplicaSet resolves the Deployment owning rsName via the
+// ReplicaSet and Deployment listers.
+func (s *Scanner) deploymentForReplicaSet(namespace, rsName string) (*appsv1.Deployment, error) {
+	rs, err := s.replicaSetLister.ReplicaSets(namespace).Get(rsName)
+	if err != nil {
+		return nil, err
+	}
+	if len(rs.OwnerReferences) == 0 {
+		return nil, fmt.Errorf("replicaset %s/%s has no owner", namespace, rsName)
+	}
+	return s.deploymentLister.Deployments(namespace).Get(rs.OwnerReferences[0].Name)
+}
+
+// cronJobForJob resolves the CronJob owning job, if any. hasCron is false
+// with a nil error for a Job that isn't owned by a CronJob.
+func (s *Scanner) cronJobForJob(job *batchv1.Job) (cron *batchv1.CronJob, hasCron bool, err error) {
+	if len(job.OwnerReferences) == 0 {
+		return nil, false, nil
+	}
+	cron, err = s.cronJobLister.CronJobs(job.Namespace).Get(job.OwnerReferences[0].Name)
+	if err != nil {
+		return nil, false, err
+	}
+	return cron, true, nil
+}
@@ -0,0 +1,70 @@
+package clusterscan
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/scan"
+)
+
+// ScanNamespace evaluates every pod-owning workload in namespace against
+// engine, from the informer caches, and appends any violations to rpt. Each
+// workload is evaluated at most once even when many of its pods are cached,
+// by deduping on kind+name.
+func (s *Scanner) ScanNamespace(namespace corev1.Namespace, engine *rules.Engine, rpt *report.Report) error {
+	namespaceName := namespace.Name
+
+	pods, err := s.podLister.Pods(namespaceName).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("unable to list pods: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	errorList := make([]error, 0)
+
+	for _, pod := range pods {
+		kind, name, spec, ok, err := s.resolveOwner(pod)
+		if err != nil {
+			errorList = append(errorList, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		key := kind + "/" + name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		scan.Workload(engine, spec, namespaceName, kind, name, nil, rpt)
+	}
+
+	if len(errorList) > 0 {
+		return utilerrors.NewAggregate(errorList)
+	}
+	return nil
+}
+
+// evaluatePod evaluates a single pod's workload and invokes onFinding for
+// each violation found. Used by Watch, where each pod event is handled as
+// it arrives rather than batched per namespace.
+func (s *Scanner) evaluatePod(pod *corev1.Pod, engine *rules.Engine, onFinding func(report.Finding)) error {
+	kind, name, spec, ok, err := s.resolveOwner(pod)
+	if err != nil || !ok {
+		return err
+	}
+
+	tmp := &report.Report{Mode: report.ModeCluster}
+	scan.Workload(engine, spec, pod.Namespace, kind, name, nil, tmp)
+	for _, f := range tmp.Findings {
+		onFinding(f)
+	}
+	return nil
+}
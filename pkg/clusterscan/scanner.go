@@ -0,0 +1,166 @@
+package clusterscan
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/informers"
+	appsv1informers "k8s.io/client-go/informers/apps/v1"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+// Scanner serves ReplicaSet/Deployment/DaemonSet/StatefulSet/Job/CronJob
+// owner lookups from a SharedInformerFactory's local caches, with a single
+// LIST+WATCH per resource type rather than one API call per pod.
+type Scanner struct {
+	clientset kubernetes.Interface
+	factory   informers.SharedInformerFactory
+
+	pods         corev1informers.PodInformer
+	deployments  appsv1informers.DeploymentInformer
+	replicaSets  appsv1informers.ReplicaSetInformer
+	daemonSets   appsv1informers.DaemonSetInformer
+	statefulSets appsv1informers.StatefulSetInformer
+	jobs         batchv1informers.JobInformer
+	cronJobs     batchv1informers.CronJobInformer
+
+	podLister         corev1listers.PodLister
+	deploymentLister  appsv1listers.DeploymentLister
+	replicaSetLister  appsv1listers.ReplicaSetLister
+	daemonSetLister   appsv1listers.DaemonSetLister
+	statefulSetLister appsv1listers.StatefulSetLister
+	jobLister         batchv1listers.JobLister
+	cronJobLister     batchv1listers.CronJobLister
+}
+
+// NewScanner builds a Scanner backed by a SharedInformerFactory with the
+// given resync period. Call Start before scanning or watching.
+func NewScanner(clientset kubernetes.Interface, resync time.Duration) *Scanner {
+	factory := informers.NewSharedInformerFactory(clientset, resync)
+
+	s := &Scanner{
+		clientset:    clientset,
+		factory:      factory,
+		pods:         factory.Core().V1().Pods(),
+		deployments:  factory.Apps().V1().Deployments(),
+		replicaSets:  factory.Apps().V1().ReplicaSets(),
+		daemonSets:   factory.Apps().V1().DaemonSets(),
+		statefulSets: factory.Apps().V1().StatefulSets(),
+		jobs:         factory.Batch().V1().Jobs(),
+		cronJobs:     factory.Batch().V1().CronJobs(),
+	}
+
+	s.podLister = s.pods.Lister()
+	s.deploymentLister = s.deployments.Lister()
+	s.replicaSetLister = s.replicaSets.Lister()
+	s.daemonSetLister = s.daemonSets.Lister()
+	s.statefulSetLister = s.statefulSets.Lister()
+	s.jobLister = s.jobs.Lister()
+	s.cronJobLister = s.cronJobs.Lister()
+
+	return s
+}
+
+// Start starts all informers and blocks until their caches have synced or
+// ctx is done.
+func (s *Scanner) Start(ctx context.Context) error {
+	s.factory.Start(ctx.Done())
+	for informerType, synced := range s.factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("cache did not sync for %v", informerType)
+		}
+	}
+	return nil
+}
+
+// DefaultConcurrency picks a worker pool size for scanning namespaceCount
+// namespaces: one goroutine per CPU, capped at one per namespace so small
+// clusters don't spin up idle workers.
+func DefaultConcurrency(namespaceCount int) int {
+	cpu := runtime.NumCPU()
+	if cpu < 1 {
+		cpu = 1
+	}
+	if namespaceCount > 0 && namespaceCount < cpu {
+		return namespaceCount
+	}
+	return cpu
+}
+
+// ScanAll scans namespaceName, or every namespace when namespaceName is
+// "ALL", appending findings to rpt. Namespaces are scanned concurrently,
+// bounded by concurrency (DefaultConcurrency is used when concurrency <= 0).
+// Per-namespace errors are aggregated rather than failing the whole scan.
+func (s *Scanner) ScanAll(ctx context.Context, namespaceName string, engine *rules.Engine, rpt *report.Report, concurrency int) error {
+	namespaces, err := s.resolveNamespaces(ctx, namespaceName)
+	if err != nil {
+		return err
+	}
+
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency(len(namespaces))
+	}
+
+	var (
+		mu    sync.Mutex
+		errs  []error
+		sem   = make(chan struct{}, concurrency)
+		group sync.WaitGroup
+	)
+
+	for _, namespace := range namespaces {
+		namespace := namespace
+		group.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer group.Done()
+			defer func() { <-sem }()
+
+			nsReport := &report.Report{Mode: rpt.Mode}
+			scanErr := s.ScanNamespace(namespace, engine, nsReport)
+
+			mu.Lock()
+			defer mu.Unlock()
+			rpt.Findings = append(rpt.Findings, nsReport.Findings...)
+			if scanErr != nil {
+				errs = append(errs, scanErr)
+			}
+		}()
+	}
+	group.Wait()
+
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+	return nil
+}
+
+func (s *Scanner) resolveNamespaces(ctx context.Context, namespaceName string) ([]corev1.Namespace, error) {
+	if namespaceName != "ALL" {
+		namespace, err := s.clientset.CoreV1().Namespaces().Get(ctx, namespaceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch namespace %q: %v", namespaceName, err)
+		}
+		return []corev1.Namespace{*namespace}, nil
+	}
+
+	namespaceList, err := s.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list namespaces: %v", err)
+	}
+	return namespaceList.Items, nil
+}
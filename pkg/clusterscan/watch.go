@@ -0,0 +1,49 @@
+package clusterscan
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+// Watch starts informers and keeps them running, invoking onFinding for
+// every violation found on a pod that's added or updated. It blocks until
+// ctx is done. namespaceName restricts evaluation to one namespace, or
+// "ALL" for the whole cluster.
+func Watch(ctx context.Context, clientset kubernetes.Interface, namespaceName string, engine *rules.Engine, onFinding func(report.Finding)) error {
+	scanner := NewScanner(clientset, defaultResync)
+
+	handle := func(obj interface{}) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return
+		}
+		if namespaceName != "ALL" && pod.Namespace != namespaceName {
+			return
+		}
+		if err := scanner.evaluatePod(pod, engine, onFinding); err != nil {
+			fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		}
+	}
+
+	if _, err := scanner.pods.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    handle,
+		UpdateFunc: func(_, newObj interface{}) { handle(newObj) },
+	}); err != nil {
+		return fmt.Errorf("unable to watch pods: %w", err)
+	}
+
+	if err := scanner.Start(ctx); err != nil {
+		return fmt.Errorf("unable to start informers: %w", err)
+	}
+
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,54 @@
+package incluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+)
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// WriteReportConfigMap writes rpt as JSON into a ConfigMap named name in the
+// pod's own namespace, creating it if it doesn't exist yet or updating it
+// otherwise. It's called by the `run --in-cluster` entrypoint, never by the
+// bootstrapping side, since only the scan pod has a report to write.
+func WriteReportConfigMap(ctx context.Context, clientset kubernetes.Interface, name string, rpt *report.Report) error {
+	data, err := json.Marshal(rpt)
+	if err != nil {
+		return fmt.Errorf("unable to marshal report: %w", err)
+	}
+
+	namespace := podNamespace()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       map[string]string{"report.json": string(data)},
+	}
+
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if isAlreadyExists(err) {
+		_, err = clientset.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write report configmap %q: %w", name, err)
+	}
+	return nil
+}
+
+// podNamespace returns the namespace the running pod belongs to, read from
+// the projected ServiceAccount volume, falling back to "default" outside a
+// cluster.
+func podNamespace() string {
+	data, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return "default"
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,138 @@
+// Package incluster bootstraps a Kubernetes Job (or recurring CronJob) that
+// runs a cluster-wide scan using in-cluster credentials, so teams can run
+// recurring audits without shipping a kubeconfig to CI.
+package incluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const appName = "detector-for-docker-socket"
+
+// Options configures the scan Job/CronJob and the RBAC objects it runs
+// under.
+type Options struct {
+	Image           string
+	Namespace       string
+	ServiceAccount  string
+	NodeSelector    map[string]string
+	Tolerations     []corev1.Toleration
+	OutputConfigMap string
+	Schedule        string
+	Rules           string
+	FailOn          string
+}
+
+// Bootstrap creates the RBAC objects and a Job (or CronJob, when
+// opts.Schedule is set) that runs a cluster-wide scan. For a one-shot Job it
+// waits for completion, streams the scan pod's logs to out, and tears the
+// Job and its RBAC objects back down. A CronJob is left running - there's
+// nothing to wait for or clean up.
+func Bootstrap(ctx context.Context, clientset kubernetes.Interface, opts Options, out io.Writer) error {
+	createdSA, err := ensureRBAC(ctx, clientset, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Schedule != "" {
+		cron := buildCronJob(opts)
+		if _, err := clientset.BatchV1().CronJobs(opts.Namespace).Create(ctx, cron, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("unable to create CronJob: %w", err)
+		}
+		fmt.Fprintf(out, "created CronJob %s/%s with schedule %q\n", opts.Namespace, cron.Name, opts.Schedule)
+		return nil
+	}
+
+	job := buildJob(opts)
+	created, err := clientset.BatchV1().Jobs(opts.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to create Job: %w", err)
+	}
+	defer cleanup(ctx, clientset, opts, created.Name, createdSA)
+
+	waitErr := waitForJob(ctx, clientset, opts.Namespace, created.Name)
+
+	if logErr := streamJobLogs(ctx, clientset, opts.Namespace, created.Name, out); logErr != nil {
+		fmt.Fprintf(out, "unable to stream scan pod logs: %v\n", logErr)
+	}
+
+	return waitErr
+}
+
+func waitForJob(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 15*time.Minute, true, func(ctx context.Context) (bool, error) {
+		job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if job.Status.Succeeded > 0 {
+			return true, nil
+		}
+		if job.Status.Failed > 0 {
+			return false, fmt.Errorf("scan job %s/%s failed", namespace, name)
+		}
+		return false, nil
+	})
+}
+
+func streamJobLogs(ctx context.Context, clientset kubernetes.Interface, namespace, jobName string, out io.Writer) error {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + jobName})
+	if err != nil {
+		return fmt.Errorf("unable to find scan pod: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("no pod found for job %s/%s", namespace, jobName)
+	}
+
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(pods.Items[0].Name, &corev1.PodLogOptions{}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to stream logs: %w", err)
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(out, stream)
+	return err
+}
+
+// cleanup removes everything Bootstrap created for a one-shot Job run. It's
+// best-effort: a failure to delete one object doesn't stop it from trying
+// the rest. The ServiceAccount is only deleted when deleteSA is true, i.e.
+// this run is the one that created it - opts.ServiceAccount may name a
+// pre-existing, possibly shared account that cleanup has no business
+// removing.
+func cleanup(ctx context.Context, clientset kubernetes.Interface, opts Options, jobName string, deleteSA bool) {
+	background := metav1.DeletePropagationBackground
+	_ = clientset.BatchV1().Jobs(opts.Namespace).Delete(ctx, jobName, metav1.DeleteOptions{PropagationPolicy: &background})
+	_ = clientset.RbacV1().ClusterRoleBindings().Delete(ctx, rbacName(opts), metav1.DeleteOptions{})
+	_ = clientset.RbacV1().ClusterRoles().Delete(ctx, rbacName(opts), metav1.DeleteOptions{})
+	if opts.OutputConfigMap != "" {
+		_ = clientset.RbacV1().RoleBindings(opts.Namespace).Delete(ctx, configMapRBACName(opts), metav1.DeleteOptions{})
+		_ = clientset.RbacV1().Roles(opts.Namespace).Delete(ctx, configMapRBACName(opts), metav1.DeleteOptions{})
+	}
+	if deleteSA {
+		_ = clientset.CoreV1().ServiceAccounts(opts.Namespace).Delete(ctx, opts.ServiceAccount, metav1.DeleteOptions{})
+	}
+}
+
+func rbacName(opts Options) string {
+	return fmt.Sprintf("%s-%s", appName, opts.ServiceAccount)
+}
+
+func configMapRBACName(opts Options) string {
+	return rbacName(opts) + "-configmap"
+}
+
+// isAlreadyExists is a small alias kept local to this package so rbac.go
+// doesn't need its own import of apierrors.
+func isAlreadyExists(err error) bool {
+	return apierrors.IsAlreadyExists(err)
+}
@@ -0,0 +1,76 @@
+package incluster
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// buildPodSpec is the scan pod both buildJob and buildCronJob run: the same
+// binary, invoked as `run --in-cluster` so it scans using the ServiceAccount
+// it's running under instead of a kubeconfig.
+func buildPodSpec(opts Options) corev1.PodSpec {
+	args := []string{"run", "--in-cluster", "--fail-on", opts.FailOn}
+	if opts.Rules != "" {
+		args = append(args, "--rules", opts.Rules)
+	}
+	if opts.OutputConfigMap != "" {
+		args = append(args, "--output-configmap", opts.OutputConfigMap)
+	}
+
+	return corev1.PodSpec{
+		ServiceAccountName: opts.ServiceAccount,
+		RestartPolicy:      corev1.RestartPolicyNever,
+		NodeSelector:       opts.NodeSelector,
+		Tolerations:        opts.Tolerations,
+		Containers: []corev1.Container{
+			{
+				Name:  appName,
+				Image: opts.Image,
+				Args:  args,
+			},
+		},
+	}
+}
+
+func buildJob(opts Options) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: appName + "-",
+			Namespace:    opts.Namespace,
+			Labels:       map[string]string{"app": appName},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: int32Ptr(0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": appName}},
+				Spec:       buildPodSpec(opts),
+			},
+		},
+	}
+}
+
+func buildCronJob(opts Options) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      appName,
+			Namespace: opts.Namespace,
+			Labels:    map[string]string{"app": appName},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: opts.Schedule,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": appName}},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: int32Ptr(0),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": appName}},
+						Spec:       buildPodSpec(opts),
+					},
+				},
+			},
+		},
+	}
+}
+
+func int32Ptr(i int32) *int32 { return &i }
@@ -0,0 +1,99 @@
+package incluster
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ensureRBAC creates the ServiceAccount and the read-only ClusterRole/
+// ClusterRoleBinding the scan pod runs under, plus a namespace-scoped
+// ConfigMap Role/RoleBinding when opts.OutputConfigMap is set. It's safe to
+// call repeatedly - objects that already exist are left as-is. createdSA
+// reports whether this call is the one that created the ServiceAccount, so
+// cleanup can avoid deleting one that was already there (e.g. a shared SA
+// passed via --service-account).
+func ensureRBAC(ctx context.Context, clientset kubernetes.Interface, opts Options) (createdSA bool, err error) {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: opts.ServiceAccount, Namespace: opts.Namespace},
+	}
+	if _, err := clientset.CoreV1().ServiceAccounts(opts.Namespace).Create(ctx, sa, metav1.CreateOptions{}); err != nil {
+		if !isAlreadyExists(err) {
+			return false, fmt.Errorf("unable to create service account: %w", err)
+		}
+	} else {
+		createdSA = true
+	}
+
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: rbacName(opts)},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods", "namespaces"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"apps"},
+				Resources: []string{"deployments", "daemonsets", "statefulsets", "replicasets"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"batch"},
+				Resources: []string{"jobs", "cronjobs"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+	if _, err := clientset.RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{}); err != nil && !isAlreadyExists(err) {
+		return createdSA, fmt.Errorf("unable to create cluster role: %w", err)
+	}
+
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: rbacName(opts)},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: role.Name},
+		Subjects: []rbacv1.Subject{
+			{Kind: "ServiceAccount", Name: opts.ServiceAccount, Namespace: opts.Namespace},
+		},
+	}
+	if _, err := clientset.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{}); err != nil && !isAlreadyExists(err) {
+		return createdSA, fmt.Errorf("unable to create cluster role binding: %w", err)
+	}
+
+	if opts.OutputConfigMap != "" {
+		if err := ensureConfigMapRBAC(ctx, clientset, opts); err != nil {
+			return createdSA, err
+		}
+	}
+	return createdSA, nil
+}
+
+// ensureConfigMapRBAC grants the scan pod's ServiceAccount permission to
+// write the ConfigMap named by opts.OutputConfigMap in opts.Namespace.
+func ensureConfigMapRBAC(ctx context.Context, clientset kubernetes.Interface, opts Options) error {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: configMapRBACName(opts), Namespace: opts.Namespace},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "create", "update"}},
+		},
+	}
+	if _, err := clientset.RbacV1().Roles(opts.Namespace).Create(ctx, role, metav1.CreateOptions{}); err != nil && !isAlreadyExists(err) {
+		return fmt.Errorf("unable to create role: %w", err)
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: role.Name, Namespace: opts.Namespace},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: role.Name},
+		Subjects: []rbacv1.Subject{
+			{Kind: "ServiceAccount", Name: opts.ServiceAccount, Namespace: opts.Namespace},
+		},
+	}
+	if _, err := clientset.RbacV1().RoleBindings(opts.Namespace).Create(ctx, binding, metav1.CreateOptions{}); err != nil && !isAlreadyExists(err) {
+		return fmt.Errorf("unable to create role binding: %w", err)
+	}
+	return nil
+}
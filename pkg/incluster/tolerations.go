@@ -0,0 +1,39 @@
+package incluster
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ParseTolerations parses --toleration values of the form
+// "key[=value][:effect]" (value is omitted for an Exists toleration) into
+// corev1.Tolerations.
+func ParseTolerations(raw []string) []corev1.Toleration {
+	tolerations := make([]corev1.Toleration, 0, len(raw))
+	for _, r := range raw {
+		tolerations = append(tolerations, parseToleration(r))
+	}
+	return tolerations
+}
+
+func parseToleration(s string) corev1.Toleration {
+	keyValue, effect := s, ""
+	if idx := strings.LastIndex(s, ":"); idx != -1 {
+		keyValue, effect = s[:idx], s[idx+1:]
+	}
+
+	key, value := keyValue, ""
+	operator := corev1.TolerationOpExists
+	if idx := strings.Index(keyValue, "="); idx != -1 {
+		key, value = keyValue[:idx], keyValue[idx+1:]
+		operator = corev1.TolerationOpEqual
+	}
+
+	return corev1.Toleration{
+		Key:      key,
+		Operator: operator,
+		Value:    value,
+		Effect:   corev1.TaintEffect(effect),
+	}
+}
@@ -0,0 +1,48 @@
+package manifest
+
+import (
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+// RenderChart runs the equivalent of `helm template` against the chart at
+// chartPath via the Helm SDK, optionally overriding its default values from
+// valuesPath, and returns the rendered manifest YAML for scanning.
+func RenderChart(chartPath, valuesPath string) ([]byte, error) {
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load chart %q: %w", chartPath, err)
+	}
+
+	values := map[string]interface{}{}
+	if valuesPath != "" {
+		values, err = chartutil.ReadValuesFile(valuesPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read values file %q: %w", valuesPath, err)
+		}
+	}
+
+	settings := cli.New()
+	actionConfig := new(action.Configuration)
+	if err := actionConfig.Init(settings.RESTClientGetter(), settings.Namespace(), "memory", func(string, ...interface{}) {}); err != nil {
+		return nil, fmt.Errorf("unable to initialize helm: %w", err)
+	}
+
+	install := action.NewInstall(actionConfig)
+	install.DryRun = true
+	install.ClientOnly = true
+	install.ReleaseName = "docker-socket-detector"
+	install.Namespace = settings.Namespace()
+	install.IncludeCRDs = true
+
+	rel, err := install.Run(chrt, values)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render chart %q: %w", chartPath, err)
+	}
+
+	return []byte(rel.Manifest), nil
+}
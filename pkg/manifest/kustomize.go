@@ -0,0 +1,25 @@
+package manifest
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// RenderKustomize builds the kustomization rooted at path via the Kustomize
+// API and returns the resulting manifest YAML for scanning.
+func RenderKustomize(path string) ([]byte, error) {
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+
+	resMap, err := k.Run(filesys.MakeFsOnDisk(), path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build kustomization %q: %w", path, err)
+	}
+
+	out, err := resMap.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("unable to render kustomization %q: %w", path, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,134 @@
+// Package manifest scans Kubernetes manifests that aren't running in a live
+// cluster: raw YAML files and directories, Helm chart output, and Kustomize
+// builds. Every document is decoded into its typed API object so the same
+// rule engine used against a cluster produces identical findings offline.
+package manifest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/scan"
+)
+
+// Scanner decodes YAML manifests and evaluates each PodSpec-bearing
+// resource it finds against Engine.
+type Scanner struct {
+	Engine *rules.Engine
+}
+
+// New returns a Scanner that evaluates manifests against engine.
+func New(engine *rules.Engine) *Scanner {
+	return &Scanner{Engine: engine}
+}
+
+// ScanReader decodes every YAML document read from r and appends any
+// findings to rpt. sourceName labels where the findings came from (a file
+// path, "stdin", or a chart/kustomize path) and is attached to each finding
+// together with the line the document starts on.
+func (s *Scanner) ScanReader(r io.Reader, sourceName string, rpt *report.Report) error {
+	docs, err := splitYAMLDocuments(r)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(doc.content, nil, nil)
+		if err != nil {
+			// Not a Kubernetes object we recognize (CRD, Helm NOTES.txt
+			// fragment, comment-only document, etc) - nothing to scan.
+			continue
+		}
+
+		kind, namespace, name, spec, ok := podSpecOf(obj)
+		if !ok {
+			continue
+		}
+
+		source := &report.Location{File: sourceName, Line: doc.line}
+		scan.Workload(s.Engine, spec, namespace, kind, name, source, rpt)
+	}
+
+	return nil
+}
+
+// podSpecOf extracts the pod spec and identifying fields from any of the
+// workload kinds that carry one. ok is false for kinds we don't walk.
+//
+// kind uses the same lowercase vocabulary clusterscan does (pod, deployment,
+// daemonset, statefulset, job, cronjob), so a finding for the same workload
+// looks identical whether it came from a file or a live cluster scan. A
+// ReplicaSet document is reported as replicaset; clusterscan reports the
+// pods of a ReplicaSet under its owning deployment instead.
+func podSpecOf(obj interface{}) (kind, namespace, name string, spec corev1.PodSpec, ok bool) {
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		return "pod", t.Namespace, t.Name, t.Spec, true
+	case *appsv1.Deployment:
+		return "deployment", t.Namespace, t.Name, t.Spec.Template.Spec, true
+	case *appsv1.DaemonSet:
+		return "daemonset", t.Namespace, t.Name, t.Spec.Template.Spec, true
+	case *appsv1.StatefulSet:
+		return "statefulset", t.Namespace, t.Name, t.Spec.Template.Spec, true
+	case *appsv1.ReplicaSet:
+		return "replicaset", t.Namespace, t.Name, t.Spec.Template.Spec, true
+	case *batchv1.Job:
+		return "job", t.Namespace, t.Name, t.Spec.Template.Spec, true
+	case *batchv1.CronJob:
+		return "cronjob", t.Namespace, t.Name, t.Spec.JobTemplate.Spec.Template.Spec, true
+	default:
+		return "", "", "", corev1.PodSpec{}, false
+	}
+}
+
+// document is one "---"-delimited YAML document, along with the line it
+// starts on in the original stream.
+type document struct {
+	content []byte
+	line    int
+}
+
+// splitYAMLDocuments splits r on "---" document separators, tracking the
+// starting line of each document so findings can point back at it.
+func splitYAMLDocuments(r io.Reader) ([]document, error) {
+	var docs []document
+	var buf strings.Builder
+
+	startLine := 1
+	lineNo := 0
+
+	flush := func() {
+		if strings.TrimSpace(buf.String()) != "" {
+			docs = append(docs, document{content: []byte(buf.String()), line: startLine})
+		}
+		buf.Reset()
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "---" {
+			flush()
+			startLine = lineNo + 1
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
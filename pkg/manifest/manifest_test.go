@@ -0,0 +1,98 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+func testEngine(t *testing.T) *rules.Engine {
+	t.Helper()
+	e, err := rules.Default()
+	if err != nil {
+		t.Fatalf("rules.Default: %v", err)
+	}
+	return e
+}
+
+func TestSplitYAMLDocuments(t *testing.T) {
+	input := "kind: Pod\nmetadata:\n  name: a\n---\nkind: Pod\nmetadata:\n  name: b\n"
+
+	docs, err := splitYAMLDocuments(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("splitYAMLDocuments: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(docs))
+	}
+	if docs[0].line != 1 {
+		t.Errorf("first document line = %d, want 1", docs[0].line)
+	}
+	if docs[1].line != 5 {
+		t.Errorf("second document line = %d, want 5", docs[1].line)
+	}
+}
+
+func TestSplitYAMLDocumentsSkipsEmpty(t *testing.T) {
+	input := "---\n---\nkind: Pod\n"
+
+	docs, err := splitYAMLDocuments(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("splitYAMLDocuments: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("expected empty documents to be skipped, got %d", len(docs))
+	}
+}
+
+func TestScanReaderFindsHostPathMount(t *testing.T) {
+	yaml := `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bad-pod
+  namespace: default
+spec:
+  containers:
+    - name: app
+      image: busybox
+  volumes:
+    - name: docker-sock
+      hostPath:
+        path: /var/run/docker.sock
+`
+	s := New(testEngine(t))
+	rpt := &report.Report{Mode: report.ModeFile}
+
+	if err := s.ScanReader(strings.NewReader(yaml), "pod.yaml", rpt); err != nil {
+		t.Fatalf("ScanReader: %v", err)
+	}
+	if len(rpt.Findings) == 0 {
+		t.Fatal("expected at least one finding for docker.sock hostPath mount")
+	}
+
+	f := rpt.Findings[0]
+	if f.Kind != "pod" || f.Name != "bad-pod" || f.Source.File != "pod.yaml" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+}
+
+func TestScanReaderIgnoresUnrecognizedDocuments(t *testing.T) {
+	yaml := `
+apiVersion: example.com/v1
+kind: NotAWorkload
+metadata:
+  name: whatever
+`
+	s := New(testEngine(t))
+	rpt := &report.Report{Mode: report.ModeFile}
+
+	if err := s.ScanReader(strings.NewReader(yaml), "custom.yaml", rpt); err != nil {
+		t.Fatalf("ScanReader: %v", err)
+	}
+	if len(rpt.Findings) != 0 {
+		t.Errorf("expected no findings for an unrecognized object, got %v", rpt.Findings)
+	}
+}
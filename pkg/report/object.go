@@ -0,0 +1,41 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// objectPrinter adapts a Report to kubectl's printers.ResourcePrinter
+// interface (json/yaml) by round-tripping it through an Unstructured value,
+// the same trick clusterinfo_dump uses to print non-API-type payloads.
+type objectPrinter struct {
+	printer printers.ResourcePrinter
+}
+
+func (p *objectPrinter) Print(w io.Writer, report *Report) error {
+	obj, err := toUnstructured(report)
+	if err != nil {
+		return err
+	}
+	return p.printer.PrintObj(obj, w)
+}
+
+func toUnstructured(report *Report) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+
+	var content map[string]interface{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetAPIVersion("detector-for-docker-socket.alexandredevely.io/v1")
+	u.SetKind("Report")
+	return u, nil
+}
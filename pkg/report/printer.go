@@ -0,0 +1,36 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// Printer renders a Report to w in a specific output format.
+type Printer interface {
+	Print(w io.Writer, report *Report) error
+}
+
+// NewPrinter returns the Printer for the requested output format. json and
+// yaml are handled by genericclioptions.PrintFlags, the same mechanism
+// pkg/kubectl/cmd/clusterinfo_dump uses to print arbitrary objects that
+// aren't themselves API types. table and sarif are implemented locally
+// since kubectl's printer library has no equivalent for either.
+func NewPrinter(format string, printFlags *genericclioptions.PrintFlags, verbose bool) (Printer, error) {
+	switch format {
+	case "", "table":
+		return newTablePrinter(verbose), nil
+	case "sarif":
+		return newSARIFPrinter(), nil
+	case "json", "yaml":
+		printFlags.OutputFormat = &format
+		p, err := printFlags.ToPrinter()
+		if err != nil {
+			return nil, fmt.Errorf("unable to build %s printer: %w", format, err)
+		}
+		return &objectPrinter{printer: p}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q: must be table, json, yaml, or sarif", format)
+	}
+}
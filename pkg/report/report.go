@@ -0,0 +1,59 @@
+// Package report defines the structured findings produced by a scan and the
+// printers that render them, so cluster scans and manifest scans (raw files,
+// Helm charts, Kustomize overlays) all funnel through the same output
+// pipeline.
+package report
+
+import (
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+// Location pinpoints where a finding was observed in a scanned file. It is
+// nil for findings produced by a live cluster scan.
+type Location struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// Finding is a single rule violation surfaced by a scan.
+type Finding struct {
+	Namespace  string         `json:"namespace,omitempty"`
+	Kind       string         `json:"kind"`
+	Name       string         `json:"name"`
+	Container  string         `json:"container,omitempty"`
+	VolumeName string         `json:"volumeName,omitempty"`
+	HostPath   string         `json:"hostPath,omitempty"`
+	RuleID     string         `json:"ruleId"`
+	Severity   rules.Severity `json:"severity"`
+	Detail     string         `json:"detail,omitempty"`
+	Source     *Location      `json:"source,omitempty"`
+}
+
+// Mode identifies what kind of scan produced a Report, which the table
+// printer uses to pick an appropriate set of column headers.
+type Mode string
+
+const (
+	ModeCluster Mode = "cluster"
+	ModeFile    Mode = "file"
+)
+
+// Report is the full set of findings produced by one scan invocation.
+type Report struct {
+	Mode     Mode      `json:"mode"`
+	Findings []Finding `json:"findings"`
+}
+
+// Add appends f to the report.
+func (r *Report) Add(f Finding) {
+	r.Findings = append(r.Findings, f)
+}
+
+// CountBySeverity tallies findings per severity level.
+func (r *Report) CountBySeverity() map[rules.Severity]int {
+	counts := map[rules.Severity]int{}
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+// SARIF 2.1.0 output, enough of the schema for CI systems like GitHub code
+// scanning and GitLab to ingest our findings directly.
+const (
+	sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	sarifVersion   = "2.1.0"
+	toolName       = "kubectl-detector-for-docker-socket"
+	toolInfoURI    = "https://github.com/alexandredevely/kubectl-detector-for-docker-socket"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string          `json:"id"`
+	ShortDescription     sarifMessage    `json:"shortDescription"`
+	DefaultConfiguration sarifRuleConfig `json:"defaultConfiguration"`
+}
+
+type sarifRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifPrinter struct{}
+
+func newSARIFPrinter() Printer {
+	return &sarifPrinter{}
+}
+
+func (p *sarifPrinter) Print(w io.Writer, report *Report) error {
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           toolName,
+						InformationURI: toolInfoURI,
+						Rules:          sarifRules(report),
+					},
+				},
+				Results: sarifResults(report),
+			},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+func sarifRules(report *Report) []sarifRule {
+	seen := map[string]bool{}
+	var out []sarifRule
+	for _, f := range report.Findings {
+		if seen[f.RuleID] {
+			continue
+		}
+		seen[f.RuleID] = true
+		out = append(out, sarifRule{
+			ID:                   f.RuleID,
+			ShortDescription:     sarifMessage{Text: f.Detail},
+			DefaultConfiguration: sarifRuleConfig{Level: sarifLevel(f.Severity)},
+		})
+	}
+	return out
+}
+
+func sarifResults(report *Report) []sarifResult {
+	out := make([]sarifResult, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		out = append(out, sarifResult{
+			RuleID:    f.RuleID,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Detail},
+			Locations: []sarifLocation{sarifLocationFor(f)},
+		})
+	}
+	return out
+}
+
+func sarifLocationFor(f Finding) sarifLocation {
+	if f.Source != nil {
+		return sarifLocation{
+			PhysicalLocation: &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: f.Source.File},
+				Region:           sarifRegion{StartLine: f.Source.Line},
+			},
+		}
+	}
+	return sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{
+			{FullyQualifiedName: f.Namespace + "/" + f.Kind + "/" + f.Name, Kind: f.Kind},
+		},
+	}
+}
+
+func sarifLevel(s rules.Severity) string {
+	switch s {
+	case rules.SeverityCritical:
+		return "error"
+	case rules.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
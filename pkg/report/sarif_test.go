@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+func TestSARIFPrinterPrint(t *testing.T) {
+	rpt := &Report{
+		Mode: ModeFile,
+		Findings: []Finding{
+			{Kind: "pod", Name: "app-1", RuleID: "docker-sock", Severity: rules.SeverityCritical, Detail: "docker.sock mounted", Source: &Location{File: "pod.yaml", Line: 3}},
+			{Namespace: "default", Kind: "deployment", Name: "app-2", RuleID: "host-pid", Severity: rules.SeverityWarning, Detail: "host PID namespace"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := newSARIFPrinter().Print(&buf, rpt); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("unmarshal sarif output: %v", err)
+	}
+
+	if log.Version != sarifVersion {
+		t.Errorf("version = %q, want %q", log.Version, sarifVersion)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("expected one run, got %d", len(log.Runs))
+	}
+
+	rules := log.Runs[0].Tool.Driver.Rules
+	if len(rules) != 2 {
+		t.Fatalf("expected two deduped rules, got %d", len(rules))
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("expected two results, got %d", len(results))
+	}
+	if results[0].Level != "error" {
+		t.Errorf("expected critical finding to map to error level, got %q", results[0].Level)
+	}
+	if results[1].Level != "warning" {
+		t.Errorf("expected warning finding to map to warning level, got %q", results[1].Level)
+	}
+	if results[0].Locations[0].PhysicalLocation == nil {
+		t.Error("expected a physical location for a file-sourced finding")
+	}
+	if results[1].Locations[0].PhysicalLocation != nil {
+		t.Error("did not expect a physical location for a cluster finding")
+	}
+}
+
+func TestSARIFRulesDeduped(t *testing.T) {
+	rpt := &Report{
+		Findings: []Finding{
+			{RuleID: "same-rule", Severity: rules.SeverityCritical},
+			{RuleID: "same-rule", Severity: rules.SeverityCritical},
+		},
+	}
+
+	if got := sarifRules(rpt); len(got) != 1 {
+		t.Errorf("expected rules deduped by RuleID, got %d entries", len(got))
+	}
+}
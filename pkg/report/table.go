@@ -0,0 +1,49 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// tablePrinter renders findings for human consumption with the same
+// tabwriter settings the CLI has always used, adding RULE and SEVERITY
+// columns (and KIND and NAME in file mode). It prints one row per finding
+// only; verbose does not add rows for workloads without findings.
+type tablePrinter struct {
+	verbose bool
+}
+
+func newTablePrinter(verbose bool) Printer {
+	return &tablePrinter{verbose: verbose}
+}
+
+func (p *tablePrinter) Print(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 8, 8, 0, '\t', 0)
+
+	if report.Mode == ModeFile {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", "FILE", "LINE", "KIND", "NAME", "RULE", "SEVERITY", "STATUS")
+		for _, f := range report.Findings {
+			file, line := "", 0
+			if f.Source != nil {
+				file, line = f.Source.File, f.Source.Line
+			}
+			status := "violated"
+			if f.HostPath != "" {
+				status = "mounted"
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t\n", file, line, f.Kind, f.Name, f.RuleID, f.Severity, status)
+		}
+		return tw.Flush()
+	}
+
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t\n", "NAMESPACE", "TYPE", "NAME", "RULE", "SEVERITY", "STATUS")
+	for _, f := range report.Findings {
+		status := "violated"
+		if f.HostPath != "" {
+			status = "mounted"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t\n", f.Namespace, f.Kind, f.Name, f.RuleID, f.Severity, status)
+	}
+	return tw.Flush()
+}
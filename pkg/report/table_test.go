@@ -0,0 +1,47 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+func TestTablePrinterCluster(t *testing.T) {
+	rpt := &Report{
+		Mode: ModeCluster,
+		Findings: []Finding{
+			{Namespace: "default", Kind: "pod", Name: "app-1", RuleID: "docker-sock", Severity: rules.SeverityCritical, HostPath: "/var/run/docker.sock"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := newTablePrinter(false).Print(&buf, rpt); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "NAMESPACE") || !strings.Contains(out, "app-1") || !strings.Contains(out, "mounted") {
+		t.Errorf("unexpected table output: %q", out)
+	}
+}
+
+func TestTablePrinterFile(t *testing.T) {
+	rpt := &Report{
+		Mode: ModeFile,
+		Findings: []Finding{
+			{Kind: "deployment", Name: "app", RuleID: "host-pid", Severity: rules.SeverityWarning, Source: &Location{File: "deploy.yaml", Line: 12}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := newTablePrinter(false).Print(&buf, rpt); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "FILE") || !strings.Contains(out, "deploy.yaml") || !strings.Contains(out, "violated") {
+		t.Errorf("unexpected table output: %q", out)
+	}
+}
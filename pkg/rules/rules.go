@@ -0,0 +1,206 @@
+// Package rules implements the configurable detection engine used to flag
+// sensitive host mounts and risky pod security settings. Rules are normally
+// loaded from the embedded default ruleset, but callers can supply their own
+// YAML file via Load to replace it.
+package rules
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Severity classifies how serious a rule violation is.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Rank returns an ordering suitable for threshold comparisons, where a higher
+// value means more severe.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Valid reports whether s is one of the known severity levels.
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// PodCheck names a specific boolean-ish property of a pod spec that a rule
+// can match against. These cover the non-volume checks called out in the
+// default ruleset (privileged containers, host namespaces, etc).
+type PodCheck string
+
+const (
+	PodCheckPrivileged               PodCheck = "privileged"
+	PodCheckAllowPrivilegeEscalation PodCheck = "allowPrivilegeEscalation"
+	PodCheckHostPID                  PodCheck = "hostPID"
+	PodCheckHostNetwork              PodCheck = "hostNetwork"
+	PodCheckHostIPC                  PodCheck = "hostIPC"
+)
+
+// Rule describes a single detection rule. A rule matches either a host path
+// volume mount (HostPathPrefixes/HostPathRegex) or a pod-spec level property
+// (PodCheck), never both.
+type Rule struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Severity    Severity `json:"severity"`
+
+	// Volume mount matching.
+	HostPathPrefixes []string `json:"hostPathPrefixes,omitempty"`
+	HostPathRegex    string   `json:"hostPathRegex,omitempty"`
+
+	// Pod spec matching.
+	PodCheck PodCheck `json:"podCheck,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// Ruleset is the top-level document shape of a rules YAML file.
+type Ruleset struct {
+	Rules []Rule `json:"rules"`
+}
+
+//go:embed default_rules.yaml
+var defaultRulesYAML []byte
+
+// Engine evaluates volumes and pod specs against a loaded set of rules.
+type Engine struct {
+	volumeRules []Rule
+	podRules    []Rule
+}
+
+// Default returns the engine built from the ruleset embedded in the binary.
+func Default() (*Engine, error) {
+	return newEngine(defaultRulesYAML)
+}
+
+// Load builds an engine from the rules YAML file at path, replacing the
+// default ruleset entirely.
+func Load(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rules file %q: %w", path, err)
+	}
+	return newEngine(data)
+}
+
+func newEngine(data []byte) (*Engine, error) {
+	var set Ruleset
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("unable to parse rules: %w", err)
+	}
+
+	e := &Engine{}
+	for _, r := range set.Rules {
+		if !r.Severity.Valid() {
+			return nil, fmt.Errorf("rule %q: invalid severity %q", r.ID, r.Severity)
+		}
+		if r.HostPathRegex != "" {
+			re, err := regexp.Compile(r.HostPathRegex)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid hostPathRegex: %w", r.ID, err)
+			}
+			r.regex = re
+		}
+		if r.PodCheck != "" {
+			e.podRules = append(e.podRules, r)
+		} else {
+			e.volumeRules = append(e.volumeRules, r)
+		}
+	}
+	return e, nil
+}
+
+// MatchHostPath returns the first rule matching path, if any.
+func (e *Engine) MatchHostPath(path string) (Rule, bool) {
+	for _, r := range e.volumeRules {
+		if r.regex != nil && r.regex.MatchString(path) {
+			return r, true
+		}
+		for _, prefix := range r.HostPathPrefixes {
+			if matchesPrefix(path, prefix) {
+				return r, true
+			}
+		}
+	}
+	return Rule{}, false
+}
+
+// matchesPrefix reports whether path is exactly prefix or a descendant of it.
+// A prefix of "/" therefore only matches a hostPath of "/" itself (mounting
+// the entire host filesystem), and "/proc" doesn't also match unrelated
+// paths like "/processes".
+func matchesPrefix(path, prefix string) bool {
+	if path == prefix {
+		return true
+	}
+	return len(path) > len(prefix) && path[:len(prefix)] == prefix && path[len(prefix)] == '/'
+}
+
+// MatchPodSpec evaluates the pod-spec level rules (privileged, hostPID, etc)
+// against spec and returns every rule that matches.
+func (e *Engine) MatchPodSpec(spec corev1.PodSpec) []Rule {
+	var matches []Rule
+	for _, r := range e.podRules {
+		if podCheckMatches(r.PodCheck, spec) {
+			matches = append(matches, r)
+		}
+	}
+	return matches
+}
+
+func podCheckMatches(check PodCheck, spec corev1.PodSpec) bool {
+	switch check {
+	case PodCheckHostPID:
+		return spec.HostPID
+	case PodCheckHostNetwork:
+		return spec.HostNetwork
+	case PodCheckHostIPC:
+		return spec.HostIPC
+	case PodCheckPrivileged:
+		for _, c := range allContainers(spec) {
+			if c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+				return true
+			}
+		}
+		return false
+	case PodCheckAllowPrivilegeEscalation:
+		for _, c := range allContainers(spec) {
+			if c.SecurityContext != nil && c.SecurityContext.AllowPrivilegeEscalation != nil && *c.SecurityContext.AllowPrivilegeEscalation {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func allContainers(spec corev1.PodSpec) []corev1.Container {
+	containers := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	return containers
+}
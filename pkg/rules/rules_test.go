@@ -0,0 +1,125 @@
+package rules
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMatchesPrefix(t *testing.T) {
+	tests := []struct {
+		path, prefix string
+		want         bool
+	}{
+		{"/", "/", true},
+		{"/var/run/docker.sock", "/var/run/docker.sock", true},
+		{"/var/run/docker.sock", "/var/run", true},
+		{"/var/runner", "/var/run", false},
+		{"/proc", "/proc", true},
+		{"/processes", "/proc", false},
+		{"/var", "/var/run", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesPrefix(tt.path, tt.prefix); got != tt.want {
+			t.Errorf("matchesPrefix(%q, %q) = %v, want %v", tt.path, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestEngineMatchHostPath(t *testing.T) {
+	e, err := newEngine([]byte(`
+rules:
+  - id: docker-sock
+    description: Docker socket mounted
+    severity: critical
+    hostPathPrefixes:
+      - /var/run/docker.sock
+  - id: proc-regex
+    description: Host /proc mounted
+    severity: warning
+    hostPathRegex: "^/proc(/.*)?$"
+`))
+	if err != nil {
+		t.Fatalf("newEngine: %v", err)
+	}
+
+	if _, ok := e.MatchHostPath("/var/run/docker.sock"); !ok {
+		t.Error("expected docker socket path to match")
+	}
+	if _, ok := e.MatchHostPath("/proc/1/root"); !ok {
+		t.Error("expected /proc path to match via regex")
+	}
+	if _, ok := e.MatchHostPath("/etc/passwd"); ok {
+		t.Error("did not expect unrelated path to match")
+	}
+}
+
+func TestNewEngineInvalidSeverity(t *testing.T) {
+	_, err := newEngine([]byte(`
+rules:
+  - id: bad
+    description: bad severity
+    severity: extreme
+    hostPathPrefixes:
+      - /
+`))
+	if err == nil {
+		t.Fatal("expected error for invalid severity, got nil")
+	}
+}
+
+func TestNewEngineInvalidRegex(t *testing.T) {
+	_, err := newEngine([]byte(`
+rules:
+  - id: bad-regex
+    description: bad regex
+    severity: warning
+    hostPathRegex: "("
+`))
+	if err == nil {
+		t.Fatal("expected error for invalid regex, got nil")
+	}
+}
+
+func TestPodCheckMatches(t *testing.T) {
+	privileged := true
+	spec := corev1.PodSpec{
+		HostPID: true,
+		Containers: []corev1.Container{
+			{SecurityContext: &corev1.SecurityContext{Privileged: &privileged}},
+		},
+	}
+
+	if !podCheckMatches(PodCheckHostPID, spec) {
+		t.Error("expected hostPID check to match")
+	}
+	if !podCheckMatches(PodCheckPrivileged, spec) {
+		t.Error("expected privileged check to match")
+	}
+	if podCheckMatches(PodCheckHostNetwork, spec) {
+		t.Error("did not expect hostNetwork check to match")
+	}
+}
+
+func TestEngineMatchPodSpec(t *testing.T) {
+	e, err := newEngine([]byte(`
+rules:
+  - id: host-pid
+    description: Host PID namespace
+    severity: critical
+    podCheck: hostPID
+`))
+	if err != nil {
+		t.Fatalf("newEngine: %v", err)
+	}
+
+	matches := e.MatchPodSpec(corev1.PodSpec{HostPID: true})
+	if len(matches) != 1 || matches[0].ID != "host-pid" {
+		t.Fatalf("expected one match for host-pid, got %v", matches)
+	}
+
+	if matches := e.MatchPodSpec(corev1.PodSpec{}); len(matches) != 0 {
+		t.Errorf("expected no matches, got %v", matches)
+	}
+}
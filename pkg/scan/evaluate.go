@@ -0,0 +1,76 @@
+// Package scan holds the rule evaluation shared by every scan source (live
+// cluster, raw manifest file, Helm chart, Kustomize overlay), so they all
+// produce identical report.Finding shapes.
+package scan
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/report"
+	"github.com/alexandredevely/kubectl-detector-for-docker-socket/pkg/rules"
+)
+
+// Workload evaluates a pod spec's hostPath volumes and pod-level security
+// settings against engine and appends any violations to rpt. source is nil
+// for live cluster resources and set to the originating manifest location
+// for file-based scans.
+func Workload(engine *rules.Engine, spec corev1.PodSpec, namespace, kind, name string, source *report.Location, rpt *report.Report) {
+	for _, v := range spec.Volumes {
+		if v.VolumeSource.HostPath == nil {
+			continue
+		}
+		rule, matched := engine.MatchHostPath(v.VolumeSource.HostPath.Path)
+		if !matched {
+			continue
+		}
+
+		containers := containersMounting(spec, v.Name)
+		if len(containers) == 0 {
+			containers = []string{""}
+		}
+		for _, container := range containers {
+			rpt.Add(report.Finding{
+				Namespace:  namespace,
+				Kind:       kind,
+				Name:       name,
+				Container:  container,
+				VolumeName: v.Name,
+				HostPath:   v.VolumeSource.HostPath.Path,
+				RuleID:     rule.ID,
+				Severity:   rule.Severity,
+				Detail:     rule.Description,
+				Source:     source,
+			})
+		}
+	}
+
+	for _, rule := range engine.MatchPodSpec(spec) {
+		rpt.Add(report.Finding{
+			Namespace: namespace,
+			Kind:      kind,
+			Name:      name,
+			RuleID:    rule.ID,
+			Severity:  rule.Severity,
+			Detail:    rule.Description,
+			Source:    source,
+		})
+	}
+}
+
+// containersMounting returns the name of every container (including init
+// containers) in spec that mounts the volume named volumeName, so a hostPath
+// finding can say which container actually has the mount.
+func containersMounting(spec corev1.PodSpec, volumeName string) []string {
+	var names []string
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, c := range containers {
+			for _, m := range c.VolumeMounts {
+				if m.Name == volumeName {
+					names = append(names, c.Name)
+					break
+				}
+			}
+		}
+	}
+	return names
+}